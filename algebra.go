@@ -0,0 +1,70 @@
+package set
+
+// SymmetricDifference returns a new set containing the elements present in
+// exactly one of s or rhs. It is computed in one pass over each side
+// rather than as Union(s, rhs).Difference(Intersect(s, rhs)), which would
+// allocate two intermediate sets. The returned set inherits the
+// concurrency mode of the receiver (s).
+func (s *Set[T]) SymmetricDifference(rhs Set[T]) Set[T] {
+	newSet := s.emptyLike()
+	s.withMembersPair(&rhs, func(sm, rm map[T]void) {
+		for k := range sm {
+			if _, exists := rm[k]; !exists {
+				newSet.insertUnsafe(k)
+			}
+		}
+		for k := range rm {
+			if _, exists := sm[k]; !exists {
+				newSet.insertUnsafe(k)
+			}
+		}
+	})
+	newSet.finalize()
+	return newSet
+}
+
+// IsSuperset returns true if s contains every element of rhs.
+func (s *Set[T]) IsSuperset(rhs Set[T]) bool {
+	return rhs.IsSubset(*s)
+}
+
+// IsProperSubset returns true if s is a subset of rhs and the two sets are
+// not equal in size (i.e. rhs has at least one element s does not have).
+// Size and membership are compared in a single locked pass, rather than as
+// separate Size/IsSubset calls, so a concurrent mutation between them
+// can't make the result reflect two different instants.
+func (s *Set[T]) IsProperSubset(rhs Set[T]) bool {
+	result := true
+	s.withMembersPair(&rhs, func(sm, rm map[T]void) {
+		if len(sm) >= len(rm) {
+			result = false
+			return
+		}
+		for k := range sm {
+			if _, exists := rm[k]; !exists {
+				result = false
+				return
+			}
+		}
+	})
+	return result
+}
+
+// IsDisjoint returns true if s and rhs share no elements. It iterates the
+// smaller of the two sets for a faster common case.
+func (s *Set[T]) IsDisjoint(rhs Set[T]) bool {
+	result := true
+	s.withMembersPair(&rhs, func(sm, rm map[T]void) {
+		smaller, larger := sm, rm
+		if len(larger) < len(smaller) {
+			smaller, larger = larger, smaller
+		}
+		for k := range smaller {
+			if _, exists := larger[k]; exists {
+				result = false
+				return
+			}
+		}
+	})
+	return result
+}
@@ -0,0 +1,69 @@
+package set
+
+import "testing"
+
+func TestSet_SymmetricDifference(t *testing.T) {
+	setA := NewWithInitializer(1, 2, 3)
+	setB := NewWithInitializer(2, 3, 4)
+
+	diff := setA.SymmetricDifference(setB)
+	if diff.Size() != 2 || !diff.Contains(1) || !diff.Contains(4) {
+		t.Fail()
+	}
+	if diff.Contains(2) || diff.Contains(3) {
+		t.Fail()
+	}
+}
+
+func TestSet_IsSuperset(t *testing.T) {
+	setA := NewWithInitializer(1, 2, 3)
+	setB := NewWithInitializer(1, 2)
+
+	if !setA.IsSuperset(setB) {
+		t.Fail()
+	}
+	if setB.IsSuperset(setA) {
+		t.Fail()
+	}
+	if !setA.IsSuperset(setA) {
+		t.Fail()
+	}
+}
+
+func TestSet_IsProperSubset(t *testing.T) {
+	setA := NewWithInitializer(1, 2)
+	setB := NewWithInitializer(1, 2, 3)
+
+	if !setA.IsProperSubset(setB) {
+		t.Fail()
+	}
+	if setA.IsProperSubset(setA) {
+		t.Fail()
+	}
+	if setB.IsProperSubset(setA) {
+		t.Fail()
+	}
+}
+
+func TestSet_IsProperSubset_SelfReferential(t *testing.T) {
+	s := NewConcurrentWithInitializer(1, 2, 3)
+
+	runWithDeadlockGuard(t, func() {
+		if s.IsProperSubset(s) {
+			t.Fail()
+		}
+	})
+}
+
+func TestSet_IsDisjoint(t *testing.T) {
+	setA := NewWithInitializer(1, 2)
+	setB := NewWithInitializer(3, 4)
+	setC := NewWithInitializer(2, 5)
+
+	if !setA.IsDisjoint(setB) {
+		t.Fail()
+	}
+	if setA.IsDisjoint(setC) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,281 @@
+package set
+
+import "unsafe"
+
+// lockIdentity returns a stable address identifying s's underlying lock:
+// its *sync.RWMutex for single-mutex concurrent sets, its *shardedSet for
+// sharded sets, or its backing map's address for non-concurrent sets
+// (which have no lock, but still need a distinct identity so two
+// different non-concurrent sets aren't mistaken for the same one). It is
+// used purely to pick a deterministic, total order in which to lock two
+// sets together, and to detect the s.Update(s) case; the value itself has
+// no other meaning. Identity is derived with unsafe rather than reflect so
+// that bulk.go doesn't defeat the noreflect build tag (see
+// hash_noreflect.go).
+func (s *Set[T]) lockIdentity() uintptr {
+	switch {
+	case s.mutex != nil:
+		return uintptr(unsafe.Pointer(s.mutex))
+	case s.sharded != nil:
+		return uintptr(unsafe.Pointer(s.sharded))
+	default:
+		return mapIdentity(s.members)
+	}
+}
+
+// mapIdentity returns the address of m's underlying data without reflect:
+// a map value is itself a single pointer-sized word at runtime, so reading
+// it as a uintptr yields a stable per-map identity.
+func mapIdentity[T comparable](m map[T]void) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&m))
+}
+
+// lockWrite takes whatever write lock(s) s's concurrency mode requires
+// (every shard's mutex, in fixed order, for a sharded set; the single
+// RWMutex otherwise) and returns a matching unlock func. It is a no-op for
+// non-concurrent sets.
+func (s *Set[T]) lockWrite() func() {
+	switch {
+	case s.sharded != nil:
+		return s.sharded.lockAll()
+	case s.mutex != nil:
+		s.mutex.Lock()
+		return s.mutex.Unlock
+	default:
+		return func() {}
+	}
+}
+
+// lockRead is the read-lock counterpart to lockWrite.
+func (s *Set[T]) lockRead() func() {
+	switch {
+	case s.sharded != nil:
+		return s.sharded.rlockAll()
+	case s.mutex != nil:
+		s.mutex.RLock()
+		return s.mutex.RUnlock
+	default:
+		return func() {}
+	}
+}
+
+// rawMembers returns the backing map that would hold e: the relevant
+// shard's map for sharded sets, or the flat members map otherwise. It
+// must only be called while the appropriate lock from lockWrite/lockRead
+// is already held.
+func (s *Set[T]) rawMembers(e T) map[T]void {
+	if s.sharded != nil {
+		return s.sharded.shardFor(e).members
+	}
+	return s.members
+}
+
+// forEachRaw calls fn once per (map, key) pair currently stored in s,
+// covering every shard for sharded sets. It must only be called while a
+// lockWrite/lockRead is already held, and fn may safely delete from the
+// map it is given (Go permits deleting the current key during a map range).
+func (s *Set[T]) forEachRaw(fn func(m map[T]void, k T)) {
+	if s.sharded != nil {
+		for _, sh := range s.sharded.shards {
+			for k := range sh.members {
+				fn(sh.members, k)
+			}
+		}
+		return
+	}
+	for k := range s.members {
+		fn(s.members, k)
+	}
+}
+
+// withBulkMutation locks s for writing and rhs for reading (skipping the
+// rhs lock entirely if it shares s's underlying lock, e.g. s.Update(s)),
+// always taking whichever of the two locks has the lower lockIdentity
+// first. That fixed global order means two goroutines calling bulk
+// updates on the same pair of sets in opposite directions (a.Update(b)
+// and, concurrently, b.Update(a)) cannot deadlock. fn is called with rhs's
+// member view, and the change count it returns is used to decide whether
+// to recount a sharded s and notify waiters.
+func (s *Set[T]) withBulkMutation(rhs *Set[T], fn func(rm map[T]void) int) int {
+	sID, rID := s.lockIdentity(), rhs.lockIdentity()
+
+	lockBoth := func() func() {
+		if sID == rID {
+			return s.lockWrite()
+		}
+		if sID < rID {
+			unlockS := s.lockWrite()
+			unlockR := rhs.lockRead()
+			return func() { unlockR(); unlockS() }
+		}
+		unlockR := rhs.lockRead()
+		unlockS := s.lockWrite()
+		return func() { unlockS(); unlockR() }
+	}
+
+	unlock := lockBoth()
+
+	var rm map[T]void
+	if sID == rID {
+		rm = s.members
+		if s.sharded != nil {
+			// s and rhs are the same sharded set; merge for a stable view.
+			rm = make(map[T]void)
+			for _, sh := range s.sharded.shards {
+				for k := range sh.members {
+					rm[k] = voidValue
+				}
+			}
+		}
+	} else if rhs.sharded != nil {
+		rm = make(map[T]void)
+		for _, sh := range rhs.sharded.shards {
+			for k := range sh.members {
+				rm[k] = voidValue
+			}
+		}
+	} else {
+		rm = rhs.members
+	}
+
+	changed := fn(rm)
+
+	if s.sharded != nil && changed != 0 {
+		s.sharded.recount()
+	}
+	unlock()
+
+	if changed != 0 {
+		s.notifyWaiters()
+	}
+	return changed
+}
+
+// AddAll inserts every element of elems into the set in a single locked
+// batch (one write-lock acquisition rather than one per element) and
+// returns how many were not already present.
+func (s *Set[T]) AddAll(elems ...T) int {
+	if len(elems) == 0 {
+		return 0
+	}
+
+	unlock := s.lockWrite()
+	changed := 0
+	for _, e := range elems {
+		m := s.rawMembers(e)
+		if _, exists := m[e]; !exists {
+			m[e] = voidValue
+			changed++
+		}
+	}
+	if s.sharded != nil && changed > 0 {
+		s.sharded.recount()
+	}
+	unlock()
+
+	if changed > 0 {
+		s.notifyWaiters()
+	}
+	return changed
+}
+
+// RemoveAll deletes every element of elems from the set in a single
+// locked batch and returns how many were actually present.
+func (s *Set[T]) RemoveAll(elems ...T) int {
+	if len(elems) == 0 {
+		return 0
+	}
+
+	unlock := s.lockWrite()
+	changed := 0
+	for _, e := range elems {
+		m := s.rawMembers(e)
+		if _, exists := m[e]; exists {
+			delete(m, e)
+			changed++
+		}
+	}
+	if s.sharded != nil && changed > 0 {
+		s.sharded.recount()
+	}
+	unlock()
+
+	if changed > 0 {
+		s.notifyWaiters()
+	}
+	return changed
+}
+
+// Update performs an in-place union, adding every element of rhs to s,
+// and returns how many elements were actually added.
+func (s *Set[T]) Update(rhs Set[T]) int {
+	return s.withBulkMutation(&rhs, func(rm map[T]void) int {
+		changed := 0
+		for k := range rm {
+			m := s.rawMembers(k)
+			if _, exists := m[k]; !exists {
+				m[k] = voidValue
+				changed++
+			}
+		}
+		return changed
+	})
+}
+
+// DifferenceUpdate removes every element of rhs from s in place and
+// returns how many elements were actually removed.
+func (s *Set[T]) DifferenceUpdate(rhs Set[T]) int {
+	return s.withBulkMutation(&rhs, func(rm map[T]void) int {
+		changed := 0
+		for k := range rm {
+			m := s.rawMembers(k)
+			if _, exists := m[k]; exists {
+				delete(m, k)
+				changed++
+			}
+		}
+		return changed
+	})
+}
+
+// IntersectUpdate removes every element of s that is not also in rhs,
+// leaving s equal to Intersect(rhs) but without allocating a new set. It
+// returns how many elements were actually removed. RetainAll is an alias
+// kept for readability at call sites that think in terms of "keep only".
+func (s *Set[T]) IntersectUpdate(rhs Set[T]) int {
+	return s.withBulkMutation(&rhs, func(rm map[T]void) int {
+		changed := 0
+		s.forEachRaw(func(m map[T]void, k T) {
+			if _, exists := rm[k]; !exists {
+				delete(m, k)
+				changed++
+			}
+		})
+		return changed
+	})
+}
+
+// RetainAll is an alias for IntersectUpdate.
+func (s *Set[T]) RetainAll(rhs Set[T]) int {
+	return s.IntersectUpdate(rhs)
+}
+
+// SymmetricDifferenceUpdate replaces s's contents with the symmetric
+// difference of s and rhs in place (elements in exactly one of the two
+// sets): elements common to both are removed from s, elements only in
+// rhs are added to s. It returns how many elements changed.
+func (s *Set[T]) SymmetricDifferenceUpdate(rhs Set[T]) int {
+	return s.withBulkMutation(&rhs, func(rm map[T]void) int {
+		changed := 0
+		for k := range rm {
+			m := s.rawMembers(k)
+			if _, exists := m[k]; exists {
+				delete(m, k)
+			} else {
+				m[k] = voidValue
+			}
+			changed++
+		}
+		return changed
+	})
+}
@@ -0,0 +1,167 @@
+package set
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSet_AddAllRemoveAll(t *testing.T) {
+	s := New[int]()
+
+	if n := s.AddAll(1, 2, 3, 2); n != 3 {
+		t.Fatalf("got %d", n)
+	}
+	if s.Size() != 3 {
+		t.Fail()
+	}
+
+	if n := s.RemoveAll(2, 3, 99); n != 2 {
+		t.Fatalf("got %d", n)
+	}
+	if s.Size() != 1 || !s.Contains(1) {
+		t.Fail()
+	}
+}
+
+func TestSet_Update(t *testing.T) {
+	a := NewWithInitializer(1, 2)
+	b := NewWithInitializer(2, 3)
+
+	if n := a.Update(b); n != 1 {
+		t.Fatalf("got %d", n)
+	}
+	if a.Size() != 3 || !a.Contains(3) {
+		t.Fail()
+	}
+}
+
+func TestSet_DifferenceUpdate(t *testing.T) {
+	a := NewWithInitializer(1, 2, 3)
+	b := NewWithInitializer(2, 3)
+
+	if n := a.DifferenceUpdate(b); n != 2 {
+		t.Fatalf("got %d", n)
+	}
+	if a.Size() != 1 || !a.Contains(1) {
+		t.Fail()
+	}
+}
+
+func TestSet_IntersectUpdateRetainAll(t *testing.T) {
+	a := NewWithInitializer(1, 2, 3)
+	b := NewWithInitializer(2, 3, 4)
+
+	if n := a.IntersectUpdate(b); n != 1 {
+		t.Fatalf("got %d", n)
+	}
+	if a.Size() != 2 || !a.Contains(2) || !a.Contains(3) {
+		t.Fail()
+	}
+
+	c := NewWithInitializer(1, 2)
+	d := NewWithInitializer(2)
+	c.RetainAll(d)
+	if c.Size() != 1 || !c.Contains(2) {
+		t.Fail()
+	}
+}
+
+func TestSet_SymmetricDifferenceUpdate(t *testing.T) {
+	a := NewWithInitializer(1, 2, 3)
+	b := NewWithInitializer(2, 3, 4)
+
+	if n := a.SymmetricDifferenceUpdate(b); n != 3 {
+		t.Fatalf("got %d", n)
+	}
+	if a.Size() != 2 || !a.Contains(1) || !a.Contains(4) {
+		t.Fail()
+	}
+}
+
+func TestConcurrentSet_Update_NoDeadlockOnReverseCalls(t *testing.T) {
+	a := NewConcurrentWithInitializer(1, 2, 3)
+	b := NewConcurrentWithInitializer(3, 4, 5)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			a.Update(b)
+		}()
+		go func() {
+			defer wg.Done()
+			b.Update(a)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestShardedSet_AddAll(t *testing.T) {
+	s := NewConcurrentSharded[int](4)
+	if n := s.AddAll(1, 2, 3); n != 3 {
+		t.Fatalf("got %d", n)
+	}
+	if s.Size() != 3 {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_UpdateAgainstShardedRHS(t *testing.T) {
+	a := NewConcurrentShardedWithInitializer[int](4, []int{1, 2})
+	b := NewConcurrentShardedWithInitializer[int](4, []int{2, 3})
+
+	if n := a.Update(b); n != 1 {
+		t.Fatalf("got %d", n)
+	}
+	if a.Size() != 3 || !a.Contains(3) {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_DifferenceUpdateAgainstShardedRHS(t *testing.T) {
+	a := NewConcurrentShardedWithInitializer[int](4, []int{1, 2, 3})
+	b := NewConcurrentShardedWithInitializer[int](4, []int{2, 3})
+
+	if n := a.DifferenceUpdate(b); n != 2 {
+		t.Fatalf("got %d", n)
+	}
+	if a.Size() != 1 || !a.Contains(1) {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_IntersectUpdateAgainstShardedRHS(t *testing.T) {
+	a := NewConcurrentShardedWithInitializer[int](4, []int{1, 2, 3})
+	b := NewConcurrentShardedWithInitializer[int](4, []int{2, 3, 4})
+
+	if n := a.IntersectUpdate(b); n != 1 {
+		t.Fatalf("got %d", n)
+	}
+	if a.Size() != 2 || !a.Contains(2) || !a.Contains(3) {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_SymmetricDifferenceUpdateAgainstShardedRHS(t *testing.T) {
+	a := NewConcurrentShardedWithInitializer[int](4, []int{1, 2, 3})
+	b := NewConcurrentShardedWithInitializer[int](4, []int{2, 3, 4})
+
+	if n := a.SymmetricDifferenceUpdate(b); n != 3 {
+		t.Fatalf("got %d", n)
+	}
+	if a.Size() != 2 || !a.Contains(1) || !a.Contains(4) {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_UpdateSelf(t *testing.T) {
+	s := NewConcurrentShardedWithInitializer[int](4, []int{1, 2, 3})
+
+	if n := s.Update(s); n != 0 {
+		t.Fatalf("got %d", n)
+	}
+	if s.Size() != 3 {
+		t.Fail()
+	}
+}
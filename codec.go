@@ -0,0 +1,113 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the set as a JSON array of its members in
+// unspecified order. For concurrent sets, the snapshot is taken under
+// RLock (or every shard's RLock, for sharded sets) for the duration of
+// the encode.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(s.Members())
+	if err != nil {
+		return nil, fmt.Errorf("set: marshal JSON: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalJSON replaces the set's contents with the elements decoded
+// from a JSON array. Duplicate elements in the array are silently
+// collapsed. The replacement map (or shard maps) is built before any
+// lock is taken, then swapped in under Lock.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var elems []T
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return fmt.Errorf("set: unmarshal JSON: %w", err)
+	}
+	return s.replaceMembers(elems)
+}
+
+// MarshalJSONSet marshals s as a JSON array, exactly as (*Set[T]).MarshalJSON
+// does. It exists to sidestep a Go limitation: MarshalJSON has a pointer
+// receiver, so it cannot be called on a Set[T] that is not addressable
+// (for example, a map value), whereas a free generic function can always
+// take its argument's address.
+func MarshalJSONSet[T comparable](s Set[T]) ([]byte, error) {
+	return (&s).MarshalJSON()
+}
+
+// GobEncode implements gob.GobEncoder, encoding the set as its member
+// slice. For concurrent sets, the snapshot is taken under RLock.
+func (s *Set[T]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s.Members()); err != nil {
+		return nil, fmt.Errorf("set: gob encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents with
+// the decoded member slice.
+func (s *Set[T]) GobDecode(data []byte) error {
+	var elems []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&elems); err != nil {
+		return fmt.Errorf("set: gob decode: %w", err)
+	}
+	return s.replaceMembers(elems)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler in terms of GobEncode.
+func (s *Set[T]) MarshalBinary() ([]byte, error) {
+	return s.GobEncode()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler in terms of GobDecode.
+func (s *Set[T]) UnmarshalBinary(data []byte) error {
+	return s.GobDecode(data)
+}
+
+// replaceMembers swaps the set's contents for elems in one locked step:
+// the replacement map (or, for sharded sets, per-shard maps) is built
+// without holding any lock, then installed under Lock so the write lock
+// is held only long enough to swap pointers.
+func (s *Set[T]) replaceMembers(elems []T) error {
+	if s.sharded != nil {
+		n := len(s.sharded.shards)
+		byShard := make([]map[T]void, n)
+		for i := range byShard {
+			byShard[i] = make(map[T]void)
+		}
+		for _, e := range elems {
+			idx := s.sharded.hash(e) % uint64(n)
+			byShard[idx][e] = voidValue
+		}
+
+		unlock := s.sharded.lockAll()
+		for i, sh := range s.sharded.shards {
+			sh.members = byShard[i]
+		}
+		unlock()
+		s.sharded.recount()
+		s.notifyWaiters()
+		return nil
+	}
+
+	fresh := make(map[T]void, len(elems))
+	for _, e := range elems {
+		fresh[e] = voidValue
+	}
+
+	if s.mutex != nil {
+		s.mutex.Lock()
+		s.members = fresh
+		s.mutex.Unlock()
+		s.notifyWaiters()
+		return nil
+	}
+	s.members = fresh
+	return nil
+}
@@ -0,0 +1,86 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestSet_JSONRoundTrip(t *testing.T) {
+	s := NewWithInitializer("a", "b", "c")
+
+	data, err := json.Marshal(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Set[string]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Equal(decoded) {
+		t.Fail()
+	}
+}
+
+func TestSet_UnmarshalJSON_CollapsesDuplicates(t *testing.T) {
+	var s Set[int]
+	if err := json.Unmarshal([]byte(`[1,2,2,3,1]`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Size() != 3 {
+		t.Fail()
+	}
+}
+
+func TestMarshalJSONSet(t *testing.T) {
+	m := map[string]Set[int]{"a": NewWithInitializer(1, 2)}
+
+	data, err := MarshalJSONSet(m["a"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Set[int]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(m["a"]) {
+		t.Fail()
+	}
+}
+
+func TestSet_GobRoundTrip(t *testing.T) {
+	s := NewConcurrentWithInitializer(1, 2, 3)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&s); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Set[int]
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Equal(decoded) {
+		t.Fail()
+	}
+}
+
+func TestSet_BinaryRoundTrip(t *testing.T) {
+	s := NewWithInitializer("x", "y")
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Set[string]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Equal(decoded) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,67 @@
+package set
+
+import "hash/maphash"
+
+// defaultHasher returns the Hasher used by NewConcurrentSharded when the
+// caller does not supply WithHasher. Strings and integer kinds are hashed
+// directly with a maphash.Hash seeded once per set; any other comparable
+// type falls back to reflectHash (see hash_reflect.go / hash_noreflect.go).
+func defaultHasher[T comparable](seed maphash.Seed) Hasher[T] {
+	var zero T
+	switch any(zero).(type) {
+	case string:
+		return func(key T) uint64 {
+			var h maphash.Hash
+			h.SetSeed(seed)
+			h.WriteString(any(key).(string))
+			return h.Sum64()
+		}
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr:
+		return func(key T) uint64 {
+			var h maphash.Hash
+			h.SetSeed(seed)
+			writeFixedWidth(&h, key)
+			return h.Sum64()
+		}
+	default:
+		return func(key T) uint64 { return reflectHash(seed, key) }
+	}
+}
+
+// writeFixedWidth feeds the bytes of a fixed-width integer key into h
+// without allocating, covering every integer kind defaultHasher matches.
+func writeFixedWidth[T comparable](h *maphash.Hash, key T) {
+	switch v := any(key).(type) {
+	case int:
+		writeUint64(h, uint64(v))
+	case int8:
+		writeUint64(h, uint64(v))
+	case int16:
+		writeUint64(h, uint64(v))
+	case int32:
+		writeUint64(h, uint64(v))
+	case int64:
+		writeUint64(h, uint64(v))
+	case uint:
+		writeUint64(h, uint64(v))
+	case uint8:
+		writeUint64(h, uint64(v))
+	case uint16:
+		writeUint64(h, uint64(v))
+	case uint32:
+		writeUint64(h, uint64(v))
+	case uint64:
+		writeUint64(h, v)
+	case uintptr:
+		writeUint64(h, uint64(v))
+	}
+}
+
+func writeUint64(h *maphash.Hash, v uint64) {
+	var buf [8]byte
+	for i := 0; i < 8; i++ {
+		buf[i] = byte(v >> (8 * i))
+	}
+	_, _ = h.Write(buf[:])
+}
@@ -0,0 +1,12 @@
+//go:build noreflect
+
+package set
+
+import "hash/maphash"
+
+// reflectHash panics under the noreflect build tag: the reflection-based
+// fallback is stripped, so NewConcurrentSharded for non-primitive element
+// types must be given an explicit WithHasher.
+func reflectHash[T comparable](seed maphash.Seed, key T) uint64 {
+	panic("set: no default hasher for this type; build without noreflect or pass WithHasher")
+}
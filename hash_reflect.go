@@ -0,0 +1,21 @@
+//go:build !noreflect
+
+package set
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+// reflectHash derives a shard-selection hash for element types that
+// defaultHasher has no native case for, formatting the key via fmt's
+// reflection-based %#v verb. It is slower than the native paths and is
+// only reached as a last resort; callers with performance-sensitive
+// non-primitive keys should supply WithHasher instead. Building with the
+// noreflect tag removes this fallback entirely.
+func reflectHash[T comparable](seed maphash.Seed, key T) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	_, _ = fmt.Fprintf(&h, "%#v", key)
+	return h.Sum64()
+}
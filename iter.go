@@ -0,0 +1,163 @@
+package set
+
+import (
+	"iter"
+	"sync/atomic"
+)
+
+// All returns a range-over-func iterator over the set's members. For
+// concurrent sets (single-mutex or sharded), the appropriate read lock(s)
+// are held for the duration of the iteration and released as soon as the
+// consumer stops ranging (including via break), so a long-lived iteration
+// still blocks writers for its full duration.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if s.sharded != nil {
+			unlock := s.sharded.rlockAll()
+			defer unlock()
+
+			for _, sh := range s.sharded.shards {
+				for k := range sh.members {
+					if !yield(k) {
+						return
+					}
+				}
+			}
+			return
+		}
+
+		if s.mutex != nil {
+			s.mutex.RLock()
+			defer s.mutex.RUnlock()
+		}
+		for k := range s.members {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Each calls fn for every member of the set, stopping early if fn returns
+// false.
+func (s *Set[T]) Each(fn func(T) bool) {
+	for e := range s.All() {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+// Any returns true if fn returns true for at least one member, short-
+// circuiting on the first match.
+func (s *Set[T]) Any(fn func(T) bool) bool {
+	for e := range s.All() {
+		if fn(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// Every returns true if fn returns true for every member (vacuously true
+// for an empty set), short-circuiting on the first mismatch. It plays the
+// role a method named "All" would, but that name is already taken by the
+// iter.Seq iterator above.
+func (s *Set[T]) Every(fn func(T) bool) bool {
+	for e := range s.All() {
+		if !fn(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns a new set containing only the members for which fn
+// returns true. The returned set inherits the concurrency mode of the
+// receiver.
+func (s *Set[T]) Filter(fn func(T) bool) Set[T] {
+	newSet := s.emptyLike()
+	for e := range s.All() {
+		if fn(e) {
+			newSet.insertUnsafe(e)
+		}
+	}
+	newSet.finalize()
+	return newSet
+}
+
+// Pop atomically removes and returns an arbitrary member of the set. It
+// reports false if the set was empty. Pop is useful for worker-queue
+// patterns where goroutines drain a shared set of work items.
+func (s *Set[T]) Pop() (T, bool) {
+	if s.sharded != nil {
+		e, ok := s.sharded.pop()
+		if ok {
+			s.notifyWaiters()
+		}
+		return e, ok
+	}
+
+	var zero T
+	if s.mutex != nil {
+		s.mutex.Lock()
+		var e T
+		var ok bool
+		for k := range s.members {
+			delete(s.members, k)
+			e, ok = k, true
+			break
+		}
+		s.mutex.Unlock()
+
+		if ok {
+			s.notifyWaiters()
+		}
+		return e, ok
+	}
+
+	for k := range s.members {
+		delete(s.members, k)
+		return k, true
+	}
+	return zero, false
+}
+
+// pop removes and returns an arbitrary element. It first tries a single
+// shard under only that shard's own lock, rotating which shard it starts
+// at on every call (via popCursor) so concurrent poppers spread across
+// shards instead of colliding on the same one; this keeps the common case
+// (some shard has something to pop) down to one shard's lock, preserving
+// the contention reduction NewConcurrentSharded exists for in
+// worker-queue-style draining. Only when that shard turns up empty does
+// it fall back to locking every shard, in the usual fixed order, to
+// search the rest.
+func (ss *shardedSet[T]) pop() (T, bool) {
+	n := len(ss.shards)
+	start := int(atomic.AddUint64(&ss.popCursor, 1)-1) % n
+
+	first := ss.shards[start]
+	first.mutex.Lock()
+	for k := range first.members {
+		delete(first.members, k)
+		first.mutex.Unlock()
+		atomic.AddInt64(&ss.count, -1)
+		return k, true
+	}
+	first.mutex.Unlock()
+
+	unlock := ss.lockAll()
+	defer unlock()
+
+	for i := 0; i < n; i++ {
+		sh := ss.shards[(start+i)%n]
+		for k := range sh.members {
+			delete(sh.members, k)
+			atomic.AddInt64(&ss.count, -1)
+			return k, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,151 @@
+package set
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSet_All(t *testing.T) {
+	s := NewWithInitializer(1, 2, 3)
+
+	seen := New[int]()
+	for e := range s.All() {
+		seen.Add(e)
+	}
+	if !seen.Equal(s) {
+		t.Fail()
+	}
+
+	// breaking out of the range must not leave the set locked.
+	count := 0
+	for range s.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fail()
+	}
+	if !s.Add(4) { // would deadlock on a concurrent set if All() left the lock held
+		t.Fail()
+	}
+}
+
+func TestSet_EachAnyEvery(t *testing.T) {
+	s := NewWithInitializer(2, 4, 6)
+
+	visited := 0
+	s.Each(func(int) bool {
+		visited++
+		return true
+	})
+	if visited != 3 {
+		t.Fail()
+	}
+
+	if !s.Every(func(e int) bool { return e%2 == 0 }) {
+		t.Fail()
+	}
+	if s.Any(func(e int) bool { return e == 5 }) {
+		t.Fail()
+	}
+	s.Add(5)
+	if s.Every(func(e int) bool { return e%2 == 0 }) {
+		t.Fail()
+	}
+	if !s.Any(func(e int) bool { return e == 5 }) {
+		t.Fail()
+	}
+}
+
+func TestSet_Filter(t *testing.T) {
+	s := NewWithInitializer(1, 2, 3, 4, 5)
+
+	evens := s.Filter(func(e int) bool { return e%2 == 0 })
+	if evens.Size() != 2 || !evens.Contains(2) || !evens.Contains(4) {
+		t.Fail()
+	}
+}
+
+func TestSet_Pop(t *testing.T) {
+	s := NewWithInitializer("a", "b", "c")
+
+	popped := New[string]()
+	for i := 0; i < 3; i++ {
+		e, ok := s.Pop()
+		if !ok {
+			t.Fail()
+		}
+		popped.Add(e)
+	}
+	if s.Size() != 0 {
+		t.Fail()
+	}
+	if popped.Size() != 3 {
+		t.Fail()
+	}
+
+	if _, ok := s.Pop(); ok {
+		t.Fail()
+	}
+}
+
+func TestConcurrentSet_PopFiresTrigger(t *testing.T) {
+	s := NewConcurrentWithInitializer("only")
+
+	if _, ok := s.Pop(); !ok {
+		t.Fail()
+	}
+	if !s.WaitForEmptyWithTimeout(0) {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_PopFiresTrigger(t *testing.T) {
+	s := NewConcurrentShardedWithInitializer(4, []string{"only"})
+
+	if _, ok := s.Pop(); !ok {
+		t.Fail()
+	}
+	if !s.WaitForEmptyWithTimeout(0) {
+		t.Fail()
+	}
+}
+
+// TestShardedSet_PopConcurrentDrain drains a sharded set with many
+// goroutines popping concurrently, which exercises pop()'s single-shard
+// fast path (and its fallback to a full scan) without any element being
+// lost or returned twice.
+func TestShardedSet_PopConcurrentDrain(t *testing.T) {
+	const n = 500
+	elems := make([]int, n)
+	for i := range elems {
+		elems[i] = i
+	}
+	s := NewConcurrentShardedWithInitializer(8, elems)
+
+	popped := NewConcurrent[int]()
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				e, ok := s.Pop()
+				if !ok {
+					return
+				}
+				if !popped.Add(e) {
+					t.Errorf("element %v popped more than once", e)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if popped.Size() != n {
+		t.Fatalf("got %d popped elements, want %d", popped.Size(), n)
+	}
+	if s.Size() != 0 {
+		t.Fail()
+	}
+}
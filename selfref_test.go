@@ -0,0 +1,91 @@
+package set
+
+import (
+	"testing"
+	"time"
+)
+
+// runWithDeadlockGuard runs fn in a goroutine and fails t if it doesn't
+// complete within a short timeout. It guards against regressions where a
+// two-operand Set method nests rhs.withMembers inside s.withMembers and
+// reacquires the same underlying lock from the same goroutine (sync.RWMutex
+// is not reentrant), which can hang forever behind a concurrently pending
+// writer rather than merely running slowly.
+func runWithDeadlockGuard(t *testing.T, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("operation did not complete; likely deadlocked on a self-referential lock")
+	}
+}
+
+func TestConcurrentSet_SelfReferential(t *testing.T) {
+	s := NewConcurrentWithInitializer(1, 2, 3)
+
+	runWithDeadlockGuard(t, func() {
+		if r := s.Intersect(s); r.Size() != 3 {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if r := s.Difference(s); !r.IsEmpty() {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if !s.Equal(s) {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if !s.IsSubset(s) {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if r := s.SymmetricDifference(s); !r.IsEmpty() {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if s.IsDisjoint(s) {
+			t.Fail()
+		}
+	})
+}
+
+func TestShardedSet_SelfReferential(t *testing.T) {
+	s := NewConcurrentShardedWithInitializer(4, []int{1, 2, 3})
+
+	runWithDeadlockGuard(t, func() {
+		if r := s.Intersect(s); r.Size() != 3 {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if r := s.Difference(s); !r.IsEmpty() {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if !s.Equal(s) {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if !s.IsSubset(s) {
+			t.Fail()
+		}
+	})
+	runWithDeadlockGuard(t, func() {
+		if r := s.SymmetricDifference(s); !r.IsEmpty() {
+			t.Fail()
+		}
+	})
+}
@@ -12,6 +12,7 @@ package set
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 )
@@ -26,29 +27,30 @@ var voidValue void
 // Concurrency:
 //   - Sets constructed via NewConcurrent or NewConcurrentWithInitializer
 //     synchronize method calls using an RWMutex.
+//   - Sets constructed via NewConcurrentSharded or
+//     NewConcurrentShardedWithInitializer synchronize method calls using a
+//     bank of independently locked shards; see sharded.go.
 //   - Sets constructed via New or NewWithInitializer are not synchronized and
 //     must not be accessed from multiple goroutines without external
 //     synchronization.
 type Set[T comparable] struct {
 	members map[T]void
-	mutex   *sync.RWMutex // if nil, the set is non-concurrent and performs no locking
-	trigger chan struct{} // used to indicate transition to empty set
+	mutex   *sync.RWMutex  // if nil, the set is non-concurrent and performs no locking
+	waiters *waiters[T]    // non-nil for concurrent sets; backs WaitForSize et al.
+	sharded *shardedSet[T] // non-nil for sets constructed via NewConcurrentSharded
 }
 
 // NewConcurrent returns a set that is concurrent safe.
 func NewConcurrent[T comparable]() Set[T] {
-	return Set[T]{members: make(map[T]void), mutex: new(sync.RWMutex)}
+	return Set[T]{members: make(map[T]void), mutex: new(sync.RWMutex), waiters: newWaiters[T]()}
 }
 
 // NewConcurrentWithInitializer returns a set that is concurrent safe and contains the provided initial set of members.
 func NewConcurrentWithInitializer[T comparable](members ...T) Set[T] {
-	s := Set[T]{members: make(map[T]void), mutex: new(sync.RWMutex)}
+	s := Set[T]{members: make(map[T]void), mutex: new(sync.RWMutex), waiters: newWaiters[T]()}
 	for _, v := range members {
 		s.members[v] = voidValue
 	}
-	if len(s.members) > 0 {
-		s.trigger = make(chan struct{})
-	}
 	return s
 }
 
@@ -69,13 +71,26 @@ func NewWithInitializer[T comparable](members ...T) Set[T] {
 // Add inserts e into the set.
 // It returns true if the set was modified (e was not already present), or false otherwise.
 func (s *Set[T]) Add(e T) bool {
+	if s.sharded != nil {
+		added := s.sharded.add(e)
+		if added {
+			s.notifyWaiters()
+		}
+		return added
+	}
+
 	if s.mutex != nil {
 		s.mutex.Lock()
-		defer s.mutex.Unlock()
+		_, exists := s.members[e]
+		if !exists {
+			s.members[e] = voidValue
+		}
+		s.mutex.Unlock()
 
-		if len(s.members) == 0 {
-			s.trigger = make(chan struct{})
+		if !exists {
+			s.notifyWaiters()
 		}
+		return !exists
 	}
 
 	_, exists := s.members[e]
@@ -89,21 +104,31 @@ func (s *Set[T]) Add(e T) bool {
 // Remove deletes e from the set.
 // It returns true if the set was modified (e was present), or false otherwise.
 func (s *Set[T]) Remove(e T) bool {
-	var beforeSize int
+	if s.sharded != nil {
+		removed := s.sharded.remove(e)
+		if removed {
+			s.notifyWaiters()
+		}
+		return removed
+	}
 
 	if s.mutex != nil {
 		s.mutex.Lock()
-		beforeSize = len(s.members)
-		defer s.mutex.Unlock()
+		_, exists := s.members[e]
+		if exists {
+			delete(s.members, e)
+		}
+		s.mutex.Unlock()
+
+		if exists {
+			s.notifyWaiters()
+		}
+		return exists
 	}
+
 	_, exists := s.members[e]
 	if exists {
 		delete(s.members, e)
-
-		if s.mutex != nil && beforeSize == 1 && len(s.members) == 0 {
-			close(s.trigger)
-		}
-
 		return true
 	}
 	return false
@@ -111,6 +136,14 @@ func (s *Set[T]) Remove(e T) bool {
 
 // Contains reports whether e is a voidValue of the set.
 func (s *Set[T]) Contains(e T) bool {
+	if s.sharded != nil {
+		sh := s.sharded.shardFor(e)
+		sh.mutex.RLock()
+		defer sh.mutex.RUnlock()
+		_, exists := sh.members[e]
+		return exists
+	}
+
 	if s.mutex != nil {
 		s.mutex.RLock()
 		defer s.mutex.RUnlock()
@@ -119,66 +152,133 @@ func (s *Set[T]) Contains(e T) bool {
 	return exists
 }
 
-// Intersect returns a new set containing the elements common to s and rhs.
-// The returned set inherits the concurrency mode of the receiver (s).
-func (s *Set[T]) Intersect(rhs Set[T]) Set[T] {
-	if s.mutex != nil {
-		s.mutex.RLock()
-		defer s.mutex.RUnlock()
+// emptyLike returns a new, empty set that inherits the receiver's
+// concurrency mode: non-concurrent, single-mutex concurrent, or sharded
+// concurrent with the same shard count and hasher.
+func (s *Set[T]) emptyLike() Set[T] {
+	switch {
+	case s.sharded != nil:
+		return NewConcurrentSharded[T](len(s.sharded.shards), WithHasher(s.sharded.hash))
+	case s.mutex != nil:
+		return NewConcurrent[T]()
+	default:
+		return New[T]()
 	}
+}
 
-	var newSet Set[T]
-	if s.mutex != nil {
-		newSet = NewConcurrent[T]()
-	} else {
-		newSet = New[T]()
+// finalize must be called after populating a freshly constructed set via
+// insertUnsafe; it brings the sharded aggregate count in sync with the
+// members actually inserted. It is a no-op for non-sharded sets.
+func (s *Set[T]) finalize() {
+	if s.sharded != nil {
+		s.sharded.recount()
 	}
+}
 
-	if rhs.mutex != nil {
-		rhs.mutex.RLock()
-		defer rhs.mutex.RUnlock()
+// insertUnsafe inserts e without locking or waiter notification. It must
+// only be used while populating a set that has not yet been made visible
+// to other goroutines, such as the result of Union, Intersect, Clone, etc.
+func (s *Set[T]) insertUnsafe(e T) {
+	if s.sharded != nil {
+		sh := s.sharded.shardFor(e)
+		sh.members[e] = voidValue
+		return
 	}
-	for k := range rhs.members {
-		_, exists := s.members[k]
-		if exists {
-			newSet.members[k] = voidValue
+	s.members[e] = voidValue
+}
+
+// withMembers invokes fn with a read-only view of s's members, holding
+// whatever lock(s) the receiver's concurrency mode requires for the
+// duration of the call. For sharded sets, every shard's RLock is held in
+// a fixed ascending order; if the set has more than one shard, the
+// members are merged into a temporary map since they are not stored
+// contiguously.
+func (s *Set[T]) withMembers(fn func(members map[T]void)) {
+	if s.sharded != nil {
+		unlock := s.sharded.rlockAll()
+		defer unlock()
+
+		if len(s.sharded.shards) == 1 {
+			fn(s.sharded.shards[0].members)
+			return
 		}
+
+		merged := make(map[T]void, s.sharded.size())
+		for _, sh := range s.sharded.shards {
+			for k := range sh.members {
+				merged[k] = voidValue
+			}
+		}
+		fn(merged)
+		return
 	}
-	return newSet
-}
 
-// Union returns a new set containing all elements present in either s or rhs.
-// The returned set inherits the concurrency mode of the receiver (s).
-func (s *Set[T]) Union(rhs Set[T]) Set[T] {
 	if s.mutex != nil {
 		s.mutex.RLock()
 		defer s.mutex.RUnlock()
 	}
+	fn(s.members)
+}
 
-	var newSet Set[T]
-	if s.mutex != nil {
-		newSet = NewConcurrent[T]()
-	} else {
-		newSet = New[T]()
-	}
-
-	for k := range s.members {
-		newSet.members[k] = voidValue
-	}
+// withMembersPair invokes fn once with read-only views of s's and rhs's
+// members, having locked both. If s and rhs share the same underlying
+// lock (e.g. a self-referential s.Intersect(s), or two Set values both
+// wrapping the same concurrent set), rhs's lock is not reacquired:
+// sync.RWMutex is not reentrant, so a second RLock from the same
+// goroutine can deadlock behind a writer already queued for the first
+// Lock. The same member view is passed for both sides in that case.
+func (s *Set[T]) withMembersPair(rhs *Set[T], fn func(sm, rm map[T]void)) {
+	if s.lockIdentity() == rhs.lockIdentity() {
+		s.withMembers(func(m map[T]void) {
+			fn(m, m)
+		})
+		return
+	}
+	s.withMembers(func(sm map[T]void) {
+		rhs.withMembers(func(rm map[T]void) {
+			fn(sm, rm)
+		})
+	})
+}
 
-	if rhs.mutex != nil {
-		rhs.mutex.RLock()
-		defer rhs.mutex.RUnlock()
-	}
-	for k := range rhs.members {
-		newSet.members[k] = voidValue
-	}
+// Intersect returns a new set containing the elements common to s and rhs.
+// The returned set inherits the concurrency mode of the receiver (s).
+func (s *Set[T]) Intersect(rhs Set[T]) Set[T] {
+	newSet := s.emptyLike()
+	s.withMembersPair(&rhs, func(sm, rm map[T]void) {
+		for k := range rm {
+			if _, exists := sm[k]; exists {
+				newSet.insertUnsafe(k)
+			}
+		}
+	})
+	newSet.finalize()
+	return newSet
+}
 
+// Union returns a new set containing all elements present in either s or rhs.
+// The returned set inherits the concurrency mode of the receiver (s).
+func (s *Set[T]) Union(rhs Set[T]) Set[T] {
+	newSet := s.emptyLike()
+	s.withMembers(func(sm map[T]void) {
+		for k := range sm {
+			newSet.insertUnsafe(k)
+		}
+	})
+	rhs.withMembers(func(rm map[T]void) {
+		for k := range rm {
+			newSet.insertUnsafe(k)
+		}
+	})
+	newSet.finalize()
 	return newSet
 }
 
 // Size returns the number of elements currently in the set.
 func (s *Set[T]) Size() int {
+	if s.sharded != nil {
+		return int(s.sharded.size())
+	}
 	if s.mutex != nil {
 		s.mutex.RLock()
 		defer s.mutex.RUnlock()
@@ -194,164 +294,123 @@ func (s *Set[T]) IsEmpty() bool {
 // Members returns a snapshot slice containing all members of the set.
 // The order of elements in the returned slice is unspecified.
 func (s *Set[T]) Members() []T {
-	if s.mutex != nil {
-		s.mutex.RLock()
-		defer s.mutex.RUnlock()
-	}
-
-	result := make([]T, 0, len(s.members))
-
-	for k := range s.members {
-		result = append(result, k)
-	}
+	var result []T
+	s.withMembers(func(members map[T]void) {
+		result = make([]T, 0, len(members))
+		for k := range members {
+			result = append(result, k)
+		}
+	})
 	return result
 }
 
-// Clear removes all elements from the set while maintaining its concurrent/non-concurrent state.
+// Clear removes all elements from the set while maintaining its concurrency mode.
 func (s *Set[T]) Clear() {
+	if s.sharded != nil {
+		if s.sharded.clear() > 0 {
+			s.notifyWaiters()
+		}
+		return
+	}
+
 	var beforeSize int
 
 	if s.mutex != nil {
 		s.mutex.Lock()
 		beforeSize = len(s.members)
-		defer s.mutex.Unlock()
-	}
-	clear(s.members)
+		clear(s.members)
+		s.mutex.Unlock()
 
-	if s.mutex != nil && beforeSize > 0 {
-		close(s.trigger)
+		if beforeSize > 0 {
+			s.notifyWaiters()
+		}
+		return
 	}
+
+	clear(s.members)
 }
 
 // Clone returns a new set containing all elements from the original set.
 // The returned set inherits the concurrency mode of the receiver.
 func (s *Set[T]) Clone() Set[T] {
-	if s.mutex != nil {
-		s.mutex.RLock()
-		defer s.mutex.RUnlock()
-	}
-
-	var newSet Set[T]
-	if s.mutex != nil {
-		newSet = NewConcurrent[T]()
-	} else {
-		newSet = New[T]()
-	}
-
-	for k := range s.members {
-		newSet.members[k] = voidValue
-	}
+	newSet := s.emptyLike()
+	s.withMembers(func(members map[T]void) {
+		for k := range members {
+			newSet.insertUnsafe(k)
+		}
+	})
+	newSet.finalize()
 	return newSet
 }
 
 // Difference returns a new set containing elements present in s but not in rhs.
 // The returned set inherits the concurrency mode of the receiver (s).
 func (s *Set[T]) Difference(rhs Set[T]) Set[T] {
-	if s.mutex != nil {
-		s.mutex.RLock()
-		defer s.mutex.RUnlock()
-	}
-
-	var newSet Set[T]
-	if s.mutex != nil {
-		newSet = NewConcurrent[T]()
-	} else {
-		newSet = New[T]()
-	}
-
-	if rhs.mutex != nil {
-		rhs.mutex.RLock()
-		defer rhs.mutex.RUnlock()
-	}
-
-	for k := range s.members {
-		if _, exists := rhs.members[k]; !exists {
-			newSet.members[k] = voidValue
+	newSet := s.emptyLike()
+	s.withMembersPair(&rhs, func(sm, rm map[T]void) {
+		for k := range sm {
+			if _, exists := rm[k]; !exists {
+				newSet.insertUnsafe(k)
+			}
 		}
-	}
+	})
+	newSet.finalize()
 	return newSet
 }
 
 // IsSubset returns true if all elements in s are present in rhs.
 func (s *Set[T]) IsSubset(rhs Set[T]) bool {
-	if s.mutex != nil {
-		s.mutex.RLock()
-		defer s.mutex.RUnlock()
-	}
-
-	if rhs.mutex != nil {
-		rhs.mutex.RLock()
-		defer rhs.mutex.RUnlock()
-	}
-
-	for k := range s.members {
-		if _, exists := rhs.members[k]; !exists {
-			return false
+	result := true
+	s.withMembersPair(&rhs, func(sm, rm map[T]void) {
+		for k := range sm {
+			if _, exists := rm[k]; !exists {
+				result = false
+				return
+			}
 		}
-	}
-	return true
+	})
+	return result
 }
 
 // Equal returns true if s and rhs contain exactly the same elements.
 func (s *Set[T]) Equal(rhs Set[T]) bool {
-	if s.mutex != nil {
-		s.mutex.RLock()
-		defer s.mutex.RUnlock()
-	}
-
-	if rhs.mutex != nil {
-		rhs.mutex.RLock()
-		defer rhs.mutex.RUnlock()
-	}
-
-	if len(s.members) != len(rhs.members) {
-		return false
-	}
-
-	for k := range s.members {
-		if _, exists := rhs.members[k]; !exists {
-			return false
+	result := true
+	s.withMembersPair(&rhs, func(sm, rm map[T]void) {
+		if len(sm) != len(rm) {
+			result = false
+			return
 		}
-	}
-	return true
+		for k := range sm {
+			if _, exists := rm[k]; !exists {
+				result = false
+				return
+			}
+		}
+	})
+	return result
 }
 
 // WaitForEmptyWithTimeout waits until the set becomes empty or the specified timeout elapses.
 // Returns true if the set becomes empty, false if the timeout occurs first.
 // The set may receive new elements before this method returns. Negative timeout duration is converted to a duration of 0.
+//
+// WaitForEmptyWithTimeout is preserved for backward compatibility as a
+// thin wrapper over WaitForSize(ctx, 0). It previously panicked when
+// called on a non-concurrent set; it now falls back to returning
+// s.IsEmpty() instead, since its bool-only signature has no way to
+// surface an error. Callers that need to distinguish "not concurrent"
+// from "timed out" should call WaitForSize directly.
 func (s *Set[T]) WaitForEmptyWithTimeout(timeout time.Duration) bool {
-
 	if timeout < 0 {
 		timeout = 0
 	}
 
-	if s.mutex == nil {
-		panic("cannot wait for empty on a non-concurrent set")
-	}
-
-	s.mutex.RLock()
-
-	if len(s.members) == 0 {
-		s.mutex.RUnlock()
-		return true
-	}
-
-	// Capture state before unlocking.
-	capturedChannel := s.trigger
-
-	// Release mutex.
-	s.mutex.RUnlock()
-
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	select {
-	case <-capturedChannel: // Captured lock.
-	case <-ctx.Done(): // timeout
+	err := s.WaitForSize(ctx, 0)
+	if errors.Is(err, ErrNotConcurrent) {
+		return s.IsEmpty()
 	}
-
-	// Are we empty now?
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-	return len(s.members) == 0
+	return err == nil
 }
@@ -0,0 +1,207 @@
+package set
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShardedSet_AddRemoveContains(t *testing.T) {
+	s := NewConcurrentSharded[string](4)
+
+	if s.Size() != 0 {
+		t.Fail()
+	}
+	if !s.Add("hello") {
+		t.Fail()
+	}
+	if s.Add("hello") {
+		t.Fail()
+	}
+	if !s.Contains("hello") {
+		t.Fail()
+	}
+	if s.Contains("goodbye") {
+		t.Fail()
+	}
+	if !s.Remove("hello") {
+		t.Fail()
+	}
+	if s.Remove("hello") {
+		t.Fail()
+	}
+	if s.Size() != 0 {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_Initializer(t *testing.T) {
+	s := NewConcurrentShardedWithInitializer(8, []int{1, 2, 3, 2})
+	if s.Size() != 3 {
+		t.Fail()
+	}
+	if !s.Contains(1) || !s.Contains(2) || !s.Contains(3) {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_UnionIntersectDifference(t *testing.T) {
+	a := NewConcurrentShardedWithInitializer(4, []int{1, 2})
+	b := NewConcurrentShardedWithInitializer(4, []int{2, 3})
+
+	union := a.Union(b)
+	if union.Size() != 3 {
+		t.Fail()
+	}
+
+	inter := a.Intersect(b)
+	if inter.Size() != 1 || !inter.Contains(2) {
+		t.Fail()
+	}
+
+	diff := a.Difference(b)
+	if diff.Size() != 1 || !diff.Contains(1) {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_WaitForEmptyWithTimeout(t *testing.T) {
+	s := NewConcurrentSharded[string](4)
+
+	if !s.WaitForEmptyWithTimeout(time.Millisecond) {
+		t.Fail()
+	}
+
+	s.Add("Fred")
+	if s.WaitForEmptyWithTimeout(time.Millisecond) {
+		t.Fail()
+	}
+
+	var result bool
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		result = s.WaitForEmptyWithTimeout(time.Second)
+		wg.Done()
+	}()
+
+	s.Remove("Fred")
+	wg.Wait()
+	if !result {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_ConcurrentAccess(t *testing.T) {
+	s := NewConcurrentSharded[int](8)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s.Add(base*100 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if s.Size() != 800 {
+		t.Fail()
+	}
+}
+
+// point has no native case in defaultHasher, so a set of points exercises
+// the reflectHash fallback (see hash.go / hash_reflect.go).
+type point struct {
+	X, Y int
+}
+
+func TestShardedSet_ReflectHasherFallback(t *testing.T) {
+	s := NewConcurrentSharded[point](4)
+
+	if !s.Add(point{1, 2}) {
+		t.Fail()
+	}
+	if s.Add(point{1, 2}) {
+		t.Fail()
+	}
+	if !s.Contains(point{1, 2}) {
+		t.Fail()
+	}
+	if s.Contains(point{3, 4}) {
+		t.Fail()
+	}
+	if s.Size() != 1 {
+		t.Fail()
+	}
+}
+
+func TestShardedSet_WithHasher(t *testing.T) {
+	var calls int32
+	hasher := func(key int) uint64 {
+		atomic.AddInt32(&calls, 1)
+		return uint64(key)
+	}
+
+	s := NewConcurrentSharded[int](4, WithHasher[int](hasher))
+
+	if !s.Add(7) {
+		t.Fail()
+	}
+	if !s.Contains(7) {
+		t.Fail()
+	}
+	if atomic.LoadInt32(&calls) == 0 {
+		t.Fatal("expected WithHasher's hasher to be invoked instead of defaultHasher")
+	}
+}
+
+// benchmarkMixedWorkload drives a 90% Contains / 10% Add-then-Remove
+// workload through b.RunParallel, which fans out across GOMAXPROCS
+// goroutines, letting -cpu=1,2,4,8 compare single-mutex contention
+// against sharded scaling as concurrency grows.
+func benchmarkMixedWorkload(b *testing.B, s Set[int]) {
+	const seed = 1 << 12
+	for i := 0; i < seed; i++ {
+		s.Add(i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			i++
+			if i%10 == 0 {
+				v := i % seed
+				s.Add(v)
+				s.Remove(v)
+			} else {
+				s.Contains(i % seed)
+			}
+		}
+	})
+}
+
+// BenchmarkConcurrent measures NewConcurrent's single RWMutex under the
+// mixed workload, as a baseline for BenchmarkConcurrentSharded.
+func BenchmarkConcurrent(b *testing.B) {
+	s := NewConcurrent[int]()
+	benchmarkMixedWorkload(b, s)
+}
+
+// BenchmarkConcurrentSharded measures NewConcurrentSharded under the same
+// mixed workload across increasing shard counts (up to and beyond typical
+// GOMAXPROCS values), to evidence near-linear scaling versus
+// BenchmarkConcurrent as shards increase.
+func BenchmarkConcurrentSharded(b *testing.B) {
+	for _, shards := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			s := NewConcurrentSharded[int](shards)
+			benchmarkMixedWorkload(b, s)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNotConcurrent is returned by WaitForSize, WaitForNonEmpty, and
+// WaitForContains when called on a set that was not constructed via
+// NewConcurrent, NewConcurrentWithInitializer, NewConcurrentSharded, or
+// NewConcurrentShardedWithInitializer.
+var ErrNotConcurrent = errors.New("set: cannot wait on a non-concurrent set")
+
+// waiterPredicate reports whether a pending wait condition currently
+// holds. Predicates are evaluated against the set's already-locked-and-
+// released view (via Size/Contains), so they must not be called while a
+// write lock on s is held by the same goroutine.
+type waiterPredicate[T comparable] func(s *Set[T]) bool
+
+// waiter is a single pending WaitForX call.
+type waiter[T comparable] struct {
+	predicate waiterPredicate[T]
+	done      chan struct{}
+}
+
+// waiters holds the pending conditions registered against one Set. It has
+// its own mutex so both single-mutex and sharded concurrent sets can share
+// the same notification mechanism without nesting their own locks.
+type waiters[T comparable] struct {
+	mutex   sync.Mutex
+	pending map[*waiter[T]]struct{}
+}
+
+func newWaiters[T comparable]() *waiters[T] {
+	return &waiters[T]{pending: make(map[*waiter[T]]struct{})}
+}
+
+// notifyWaiters re-evaluates every pending predicate and wakes any whose
+// condition now holds. It is called after every mutating operation (Add,
+// Remove, Clear, Pop) that actually changed the set, once any shard or
+// mutex locks taken for the mutation itself have been released, to avoid
+// deadlocking against predicates that call back into Size/Contains.
+func (s *Set[T]) notifyWaiters() {
+	if s.waiters == nil {
+		return
+	}
+
+	s.waiters.mutex.Lock()
+	if len(s.waiters.pending) == 0 {
+		s.waiters.mutex.Unlock()
+		return
+	}
+	// Copy the pending set so we can evaluate predicates without holding
+	// waiters.mutex (predicates call back into Size/Contains, which is
+	// cheap but need not happen under this lock).
+	toCheck := make([]*waiter[T], 0, len(s.waiters.pending))
+	for w := range s.waiters.pending {
+		toCheck = append(toCheck, w)
+	}
+	s.waiters.mutex.Unlock()
+
+	for _, w := range toCheck {
+		if !w.predicate(s) {
+			continue
+		}
+		s.waiters.mutex.Lock()
+		if _, stillPending := s.waiters.pending[w]; stillPending {
+			delete(s.waiters.pending, w)
+			close(w.done)
+		}
+		s.waiters.mutex.Unlock()
+	}
+}
+
+// waitFor blocks until predicate holds for s or ctx is done, whichever
+// comes first. It returns ErrNotConcurrent immediately for non-concurrent
+// sets, since there notifyWaiters is never called.
+func (s *Set[T]) waitFor(ctx context.Context, predicate waiterPredicate[T]) error {
+	if s.waiters == nil {
+		return ErrNotConcurrent
+	}
+
+	if predicate(s) {
+		return nil
+	}
+
+	w := &waiter[T]{predicate: predicate, done: make(chan struct{})}
+
+	s.waiters.mutex.Lock()
+	s.waiters.pending[w] = struct{}{}
+	s.waiters.mutex.Unlock()
+
+	// The condition may have become true between the check above and
+	// registering w; re-check once now that w would catch any notification
+	// racing with registration.
+	if predicate(s) {
+		s.waiters.mutex.Lock()
+		delete(s.waiters.pending, w)
+		s.waiters.mutex.Unlock()
+		return nil
+	}
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		s.waiters.mutex.Lock()
+		delete(s.waiters.pending, w)
+		s.waiters.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// WaitForSize blocks until the set's size equals n, ctx is done, or the
+// set is not concurrent, returning ctx.Err() in the timeout/cancellation
+// case and ErrNotConcurrent in the latter.
+func (s *Set[T]) WaitForSize(ctx context.Context, n int) error {
+	return s.waitFor(ctx, func(s *Set[T]) bool { return s.Size() == n })
+}
+
+// WaitForNonEmpty blocks until the set has at least one element or ctx is done.
+func (s *Set[T]) WaitForNonEmpty(ctx context.Context) error {
+	return s.waitFor(ctx, func(s *Set[T]) bool { return s.Size() > 0 })
+}
+
+// WaitForContains blocks until the set contains e or ctx is done.
+func (s *Set[T]) WaitForContains(ctx context.Context, e T) error {
+	return s.waitFor(ctx, func(s *Set[T]) bool { return s.Contains(e) })
+}
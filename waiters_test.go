@@ -0,0 +1,105 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSet_WaitForSize(t *testing.T) {
+	s := NewConcurrent[int]()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var err error
+	go func() {
+		defer wg.Done()
+		err = s.WaitForSize(ctx, 2)
+	}()
+
+	s.Add(1)
+	s.Add(2)
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSet_WaitForNonEmpty(t *testing.T) {
+	s := NewConcurrent[string]()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var err error
+	go func() {
+		defer wg.Done()
+		err = s.WaitForNonEmpty(ctx)
+	}()
+
+	s.Add("hello")
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSet_WaitForContains(t *testing.T) {
+	s := NewConcurrentSharded[int](4)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var err error
+	go func() {
+		defer wg.Done()
+		err = s.WaitForContains(ctx, 42)
+	}()
+
+	s.Add(1)
+	s.Add(42)
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSet_WaitForSize_Timeout(t *testing.T) {
+	s := NewConcurrent[int]()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	if err := s.WaitForSize(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fail()
+	}
+}
+
+func TestSet_WaitForSize_NotConcurrent(t *testing.T) {
+	s := New[int]()
+
+	if err := s.WaitForSize(context.Background(), 0); !errors.Is(err, ErrNotConcurrent) {
+		t.Fail()
+	}
+}
+
+func TestSet_WaitForEmptyWithTimeout_NonConcurrentFallback(t *testing.T) {
+	s := New[int]()
+
+	if !s.WaitForEmptyWithTimeout(time.Millisecond) {
+		t.Fail()
+	}
+	s.Add(1)
+	if s.WaitForEmptyWithTimeout(time.Millisecond) {
+		t.Fail()
+	}
+}